@@ -0,0 +1,42 @@
+package s3lib
+
+import (
+	"mime"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+//RegisterContentType registers a content-type to use for files with the given extension
+//(e.g. ".webp"), taking precedence over both the standard mime package and content sniffing.
+//ext should include the leading dot. Overrides are scoped to this Client instance.
+func (c *Client) RegisterContentType(ext, contentType string) {
+	c.contentTypeOverridesMu.Lock()
+	defer c.contentTypeOverridesMu.Unlock()
+	if c.contentTypeOverrides == nil {
+		c.contentTypeOverrides = map[string]string{}
+	}
+	c.contentTypeOverrides[ext] = contentType
+}
+
+//resolveContentType determines the content-type for filePath: a registered override wins,
+//then the extension is looked up via the standard mime package, and finally the first 512
+//bytes are sniffed with mimetype as a fallback for files with no/unknown extension.
+func (c *Client) resolveContentType(filePath string, ext string) (string, error) {
+
+	c.contentTypeOverridesMu.RLock()
+	override, ok := c.contentTypeOverrides[ext]
+	c.contentTypeOverridesMu.RUnlock()
+	if ok {
+		return override, nil
+	}
+
+	if byExt := mime.TypeByExtension(ext); byExt != "" {
+		return byExt, nil
+	}
+
+	sniffed, err := mimetype.DetectFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return sniffed.String(), nil
+}
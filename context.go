@@ -0,0 +1,247 @@
+package s3lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"golang.org/x/sync/errgroup"
+)
+
+//GetObjectWithContext is the context-aware variant of GetObject
+func (c *Client) GetObjectWithContext(ctx context.Context, key string, value interface{}, opts ...Options) (bool, error) {
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	firstOptions(opts).applyToGetObjectInput(input)
+
+	result, err := c.svc.GetObjectWithContext(ctx, input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			if aerr.Code() == "NoSuchKey" {
+				return false, nil
+			}
+		}
+		return false, err
+	}
+
+	defer result.Body.Close()
+	decoder := json.NewDecoder(result.Body)
+	if err := decoder.Decode(value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+//GetStringWithContext is the context-aware variant of GetString
+func (c *Client) GetStringWithContext(ctx context.Context, key string, opts ...Options) (string, error) {
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	firstOptions(opts).applyToGetObjectInput(input)
+
+	result, err := c.svc.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	defer result.Body.Close()
+	bits, err := ioutil.ReadAll(result.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bits), nil
+}
+
+//PutObjectWithContext is the context-aware variant of PutObject
+func (c *Client) PutObjectWithContext(ctx context.Context, key string, value interface{}, opts ...Options) error {
+
+	input := s3.PutObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+
+	if value != nil {
+		body, _ := json.MarshalIndent(value, "", "  ")
+		input.Body = bytes.NewReader(body)
+		input.ContentType = aws.String("application/json")
+	}
+	firstOptions(opts).applyToPutObjectInput(&input)
+
+	_, err := c.svc.PutObjectWithContext(ctx, &input)
+	return err
+}
+
+//DeleteObjectWithContext is the context-aware variant of DeleteObject
+func (c *Client) DeleteObjectWithContext(ctx context.Context, key string) error {
+	input := s3.DeleteObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	_, err := c.svc.DeleteObjectWithContext(ctx, &input)
+	return err
+}
+
+//PutContentWithContext is the context-aware variant of PutContent
+func (c *Client) PutContentWithContext(ctx context.Context, key string, body io.ReadSeeker, contentType string, opts ...Options) error {
+
+	input := s3.PutObjectInput{
+		Bucket:      aws.String(c.Bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: &contentType,
+	}
+	firstOptions(opts).applyToPutObjectInput(&input)
+
+	_, err := c.svc.PutObjectWithContext(ctx, &input)
+	return err
+}
+
+//ListWithContext is the context-aware variant of List
+func (c *Client) ListWithContext(ctx context.Context, prefix string) (*s3.ListObjectsV2Output, error) {
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.Bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+
+	return c.svc.ListObjectsV2WithContext(ctx, input)
+}
+
+//DownloadFileWithContext is the context-aware variant of DownloadFile
+func (c *Client) DownloadFileWithContext(ctx context.Context, key string, dst string, opts ...Options) error {
+
+	parts := strings.Split(key, "/")
+	file := parts[len(parts)-1]
+	objDst := filepath.Join(dst, file)
+
+	req := &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	firstOptions(opts).applyToGetObjectInput(req)
+
+	resp, err := c.svc.GetObjectWithContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(objDst), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(objDst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+//UploadFileWithContext is the context-aware variant of UploadFile
+func (c *Client) UploadFileWithContext(ctx context.Context, prefix string, dir string, filePath string, opts ...Options) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileDirectory, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+
+	relPath, err := filepath.Rel(dir, fileDirectory)
+	if err != nil {
+		return err
+	}
+	key := path.Join(prefix, filepath.ToSlash(relPath))
+
+	contentType, err := c.resolveContentType(filePath, filepath.Ext(filePath))
+	if err != nil {
+		return err
+	}
+
+	params := &s3.PutObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}
+	if contentType != "" {
+		params.ContentType = aws.String(contentType)
+	}
+	firstOptions(opts).applyToPutObjectInput(params)
+
+	_, err = c.svc.PutObjectWithContext(ctx, params)
+	return err
+}
+
+//UploadDirectoryWithContext is the context-aware variant of UploadDirectory. It runs up to
+//concurrency UploadFile workers in parallel via an errgroup, stopping early and returning the
+//first error if ctx is canceled or a worker fails. concurrency <= 1 uploads serially.
+func (c *Client) UploadDirectoryWithContext(ctx context.Context, prefix string, dir string, concurrency int) error {
+
+	fileList := []string{}
+	err := filepath.Walk(dir, func(p string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		isDir, err := isDirectory(p)
+		if err != nil {
+			return err
+		}
+		if isDir {
+			return nil
+		}
+		fileList = append(fileList, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, concurrency)
+
+	for _, file := range fileList {
+		file := file
+		select {
+		case <-ctx.Done():
+			if err := g.Wait(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		g.Go(func() error {
+			defer func() { <-sem }()
+			return c.UploadFileWithContext(ctx, prefix, dir, file)
+		})
+	}
+
+	return g.Wait()
+}
@@ -0,0 +1,83 @@
+package s3lib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+//ListAll lists every key under prefix, following ContinuationToken until the
+//result set is no longer truncated. Unlike List, it never silently caps at 1000 keys.
+func (c *Client) ListAll(prefix string) ([]*s3.Object, error) {
+
+	objects := []*s3.Object{}
+	err := c.ListPaged(prefix, 0, func(page *s3.ListObjectsV2Output) bool {
+		objects = append(objects, page.Contents...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+//ListPaged walks every page of keys under prefix, calling fn with each page in turn.
+//fn returns false to stop walking early. pageSize sets MaxKeys per request (0 uses the S3 default).
+func (c *Client) ListPaged(prefix string, pageSize int64, fn func(*s3.ListObjectsV2Output) bool) error {
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.Bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if pageSize > 0 {
+		input.MaxKeys = aws.Int64(pageSize)
+	}
+
+	for {
+		output, err := c.svc.ListObjectsV2(input)
+		if err != nil {
+			return err
+		}
+
+		if !fn(output) {
+			return nil
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return nil
+		}
+		input.ContinuationToken = output.NextContinuationToken
+	}
+}
+
+//ListCommonPrefixes lists the "directories" immediately under prefix by setting Delimiter,
+//which asks S3 to roll up everything past it into CommonPrefixes instead of individual keys
+func (c *Client) ListCommonPrefixes(prefix, delimiter string) ([]string, error) {
+
+	prefixes := []string{}
+	input := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(c.Bucket),
+		Delimiter: aws.String(delimiter),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+
+	for {
+		output, err := c.svc.ListObjectsV2(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range output.CommonPrefixes {
+			prefixes = append(prefixes, aws.StringValue(p.Prefix))
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return prefixes, nil
+		}
+		input.ContinuationToken = output.NextContinuationToken
+	}
+}
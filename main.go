@@ -9,20 +9,29 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
 //Client is an s3 client
 type Client struct {
-	session *session.Session
-	svc     *s3.S3
-	Bucket  string
-	Region  string
+	session    *session.Session
+	svc        s3iface.S3API
+	uploader   *s3manager.Uploader
+	downloader *s3manager.Downloader
+	Bucket     string
+	Region     string
+
+	contentTypeOverridesMu sync.RWMutex
+	contentTypeOverrides   map[string]string
 }
 
 //NewClient creates a new client
@@ -38,22 +47,93 @@ func NewClient(bucket string, region string) (*Client, error) {
 
 //NewClientWithSession creates a new client based on a session
 func NewClientWithSession(s *session.Session, bucket string) *Client {
+	c := NewClientWithS3API(s3.New(s), bucket)
+	c.session = s
+	c.Region = *s.Config.Region
+	return c
+}
+
+//NewClientWithS3API creates a new client from an existing s3iface.S3API implementation.
+//This allows unit tests to substitute a fake/mock instead of talking to real S3.
+func NewClientWithS3API(api s3iface.S3API, bucket string) *Client {
 	return &Client{
-		session: s,
-		svc:     s3.New(s),
-		Bucket:  bucket,
-		Region:  *s.Config.Region,
+		svc:        api,
+		uploader:   s3manager.NewUploaderWithClient(api),
+		downloader: s3manager.NewDownloaderWithClient(api),
+		Bucket:     bucket,
+	}
+}
+
+//ClientConfig holds the settings needed to talk to S3-compatible endpoints
+//(MinIO, LocalStack, Ceph RGW) or to override the default AWS credential chain.
+type ClientConfig struct {
+
+	//Region is the AWS region (or a placeholder region for non-AWS endpoints)
+	Region string
+
+	//Endpoint overrides the default AWS endpoint, e.g. http://localhost:9000 for MinIO
+	Endpoint string
+
+	//AccessKeyID, SecretAccessKey, and SessionToken set static credentials.
+	//If AccessKeyID is empty, the default AWS credential chain is used.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	//Profile selects a named profile from the shared AWS credentials file
+	Profile string
+
+	//S3ForcePathStyle forces path-style addressing (bucket.s3.amazonaws.com -> s3.amazonaws.com/bucket),
+	//which most S3-compatible servers require
+	S3ForcePathStyle bool
+
+	//DisableSSL disables HTTPS, useful for local test endpoints
+	DisableSSL bool
+}
+
+//NewClientWithConfig creates a new client for bucket using cfg, allowing it to target
+//S3-compatible endpoints like MinIO or LocalStack with custom credentials
+func NewClientWithConfig(bucket string, cfg ClientConfig) (*Client, error) {
+
+	awsConfig := &aws.Config{
+		Region:           aws.String(cfg.Region),
+		S3ForcePathStyle: aws.Bool(cfg.S3ForcePathStyle),
+		DisableSSL:       aws.Bool(cfg.DisableSSL),
+	}
+
+	if cfg.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(cfg.Endpoint)
 	}
+
+	if cfg.AccessKeyID != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)
+	}
+
+	opts := session.Options{
+		Config: *awsConfig,
+	}
+	if cfg.Profile != "" {
+		opts.Profile = cfg.Profile
+	}
+
+	sess, err := session.NewSessionWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientWithSession(sess, bucket), nil
 }
 
 //GetObject returns an object from JSON for a key.
-//Return false if key is not found.
-func (c *Client) GetObject(key string, value interface{}) (bool, error) {
+//Return false if key is not found. Pass opts to supply SSE-C customer key headers
+//for reading back an object encrypted with a customer-provided key.
+func (c *Client) GetObject(key string, value interface{}, opts ...Options) (bool, error) {
 
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(c.Bucket),
 		Key:    aws.String(key),
 	}
+	firstOptions(opts).applyToGetObjectInput(input)
 
 	result, err := c.svc.GetObject(input)
 	if err != nil {
@@ -76,12 +156,13 @@ func (c *Client) GetObject(key string, value interface{}) (bool, error) {
 }
 
 //GetString returns a string representation of a key
-func (c *Client) GetString(key string) (string, error) {
+func (c *Client) GetString(key string, opts ...Options) (string, error) {
 
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(c.Bucket),
 		Key:    aws.String(key),
 	}
+	firstOptions(opts).applyToGetObjectInput(input)
 
 	result, err := c.svc.GetObject(input)
 	if err != nil {
@@ -97,7 +178,7 @@ func (c *Client) GetString(key string) (string, error) {
 }
 
 //PutObject marshals an object to JSON and writes it to a key
-func (c *Client) PutObject(key string, value interface{}) error {
+func (c *Client) PutObject(key string, value interface{}, opts ...Options) error {
 
 	input := s3.PutObjectInput{
 		Bucket: aws.String(c.Bucket),
@@ -109,6 +190,7 @@ func (c *Client) PutObject(key string, value interface{}) error {
 		input.Body = bytes.NewReader(json)
 		input.ContentType = aws.String("application/json")
 	}
+	firstOptions(opts).applyToPutObjectInput(&input)
 
 	_, err := c.svc.PutObject(&input)
 	if err != nil {
@@ -135,7 +217,7 @@ func (c *Client) DeleteObject(key string) error {
 }
 
 //PutContent writes content to a key
-func (c *Client) PutContent(key string, body io.ReadSeeker, contentType string) error {
+func (c *Client) PutContent(key string, body io.ReadSeeker, contentType string, opts ...Options) error {
 
 	input := s3.PutObjectInput{
 		Bucket:      aws.String(c.Bucket),
@@ -143,6 +225,7 @@ func (c *Client) PutContent(key string, body io.ReadSeeker, contentType string)
 		Body:        body,
 		ContentType: &contentType,
 	}
+	firstOptions(opts).applyToPutObjectInput(&input)
 
 	_, err := c.svc.PutObject(&input)
 	if err != nil {
@@ -152,6 +235,43 @@ func (c *Client) PutContent(key string, body io.ReadSeeker, contentType string)
 	return nil
 }
 
+//PutContentStream writes body to key using the managed uploader, which splits large
+//bodies into concurrent multipart uploads instead of a single PUT. Unlike PutContent,
+//body does not need to support seeking.
+func (c *Client) PutContentStream(key string, body io.Reader, contentType string) error {
+
+	input := &s3manager.UploadInput{
+		Bucket:      aws.String(c.Bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: &contentType,
+	}
+
+	_, err := c.uploader.Upload(input)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//DownloadStream downloads key into w using the managed downloader, which fetches
+//the object in concurrent byte-range parts instead of a single GET.
+func (c *Client) DownloadStream(key string, w io.WriterAt) error {
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+
+	_, err := c.downloader.Download(w, input)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 //List lists bucket keys with an optional prefix filter
 func (c *Client) List(prefix string) (*s3.ListObjectsV2Output, error) {
 
@@ -196,8 +316,9 @@ func (c *Client) BucketKeyExists(bucket, key string) (bool, error) {
 	return true, nil
 }
 
-//DownloadFile downloads a key to a file in a local directory
-func (c *Client) DownloadFile(key string, dst string) error {
+//DownloadFile downloads a key to a file in a local directory. Pass opts to supply
+//SSE-C customer key headers for reading back an object encrypted with a customer-provided key.
+func (c *Client) DownloadFile(key string, dst string, opts ...Options) error {
 
 	// Get the object destination path
 	parts := strings.Split(key, "/")
@@ -208,6 +329,7 @@ func (c *Client) DownloadFile(key string, dst string) error {
 		Bucket: aws.String(c.Bucket),
 		Key:    aws.String(key),
 	}
+	firstOptions(opts).applyToGetObjectInput(req)
 
 	resp, err := c.svc.GetObject(req)
 	if err != nil {
@@ -256,34 +378,29 @@ func (c *Client) UploadDirectory(prefix string, dir string) error {
 }
 
 //UploadFile uploads a file to s3
-func (c *Client) UploadFile(prefix string, dir string, filePath string) error {
+func (c *Client) UploadFile(prefix string, dir string, filePath string, opts ...Options) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	var key string
-	fileDirectory, _ := filepath.Abs(filePath)
+
+	fileDirectory, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
 
 	//remove base local directory
-	fileDirectory = strings.Split(fileDirectory, dir)[1]
-	key = path.Join(prefix, fileDirectory)
-
-	//infer content-type from file extension (default to text)
-	contentType := ""
-	switch extension := filepath.Ext(filePath); extension {
-	case ".txt":
-		contentType = "text/plain"
-	case ".csv":
-		contentType = "text/csv"
-	case ".tsv":
-		contentType = "text/tsv"
-	case ".html":
-		contentType = "text/html"
-	case ".json":
-		contentType = "application/json"
-	case ".xml": //why not? :)
-		contentType = "application/xml"
+	relPath, err := filepath.Rel(dir, fileDirectory)
+	if err != nil {
+		return err
+	}
+	key := path.Join(prefix, filepath.ToSlash(relPath))
+
+	//infer content-type from file extension, falling back to content sniffing
+	contentType, err := c.resolveContentType(filePath, filepath.Ext(filePath))
+	if err != nil {
+		return err
 	}
 
 	// Upload the file to the s3 given bucket
@@ -295,6 +412,7 @@ func (c *Client) UploadFile(prefix string, dir string, filePath string) error {
 	if contentType != "" {
 		params.ContentType = aws.String(contentType)
 	}
+	firstOptions(opts).applyToPutObjectInput(params)
 	_, err = c.svc.PutObject(params)
 	if err != nil {
 		return err
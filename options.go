@@ -0,0 +1,142 @@
+package s3lib
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+//Options carries the per-request settings accepted by the Put/Get family of methods,
+//covering server-side encryption, SSE-C customer keys, caching/transfer headers, and metadata.
+type Options struct {
+
+	//SSE enables server-side encryption with AES256. Ignored if SSEKMSKeyID is set.
+	SSE bool
+
+	//SSEKMSKeyID enables server-side encryption with AWS KMS using the given key ID. An empty value
+	//does not enable KMS encryption.
+	SSEKMSKeyID string
+
+	//SSECustomerAlgorithm, SSECustomerKey, and SSECustomerKeyMD5 configure SSE-C (customer-provided keys).
+	//The same key must be supplied on the matching Get/Download call to read the object back.
+	SSECustomerAlgorithm string
+	SSECustomerKey       string
+	SSECustomerKeyMD5    string
+
+	//ContentType overrides the content-type that would otherwise be inferred for the object
+	ContentType string
+
+	CacheControl       string
+	ContentEncoding    string
+	ContentDisposition string
+	Metadata           map[string]string
+	StorageClass       string
+}
+
+//applyToPutObjectInput copies the relevant fields of o onto input
+func (o Options) applyToPutObjectInput(input *s3.PutObjectInput) {
+	if o.ContentType != "" {
+		input.ContentType = aws.String(o.ContentType)
+	}
+
+	if o.SSEKMSKeyID != "" {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		input.SSEKMSKeyId = aws.String(o.SSEKMSKeyID)
+	} else if o.SSE {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	}
+
+	if o.SSECustomerAlgorithm != "" {
+		input.SSECustomerAlgorithm = aws.String(o.SSECustomerAlgorithm)
+		input.SSECustomerKey = aws.String(o.SSECustomerKey)
+		input.SSECustomerKeyMD5 = aws.String(o.SSECustomerKeyMD5)
+	}
+
+	if o.CacheControl != "" {
+		input.CacheControl = aws.String(o.CacheControl)
+	}
+	if o.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(o.ContentEncoding)
+	}
+	if o.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(o.ContentDisposition)
+	}
+	if o.StorageClass != "" {
+		input.StorageClass = aws.String(o.StorageClass)
+	}
+	if len(o.Metadata) > 0 {
+		input.Metadata = aws.StringMap(o.Metadata)
+	}
+}
+
+//applyToGetObjectInput copies the SSE-C fields of o onto input, which is all a GET needs
+func (o Options) applyToGetObjectInput(input *s3.GetObjectInput) {
+	if o.SSECustomerAlgorithm != "" {
+		input.SSECustomerAlgorithm = aws.String(o.SSECustomerAlgorithm)
+		input.SSECustomerKey = aws.String(o.SSECustomerKey)
+		input.SSECustomerKeyMD5 = aws.String(o.SSECustomerKeyMD5)
+	}
+}
+
+//applyToHeadObjectInput copies the SSE-C fields of o onto input, which is all a HEAD needs
+func (o Options) applyToHeadObjectInput(input *s3.HeadObjectInput) {
+	if o.SSECustomerAlgorithm != "" {
+		input.SSECustomerAlgorithm = aws.String(o.SSECustomerAlgorithm)
+		input.SSECustomerKey = aws.String(o.SSECustomerKey)
+		input.SSECustomerKeyMD5 = aws.String(o.SSECustomerKeyMD5)
+	}
+}
+
+//firstOptions returns the first element of opts, or the zero value if opts is empty.
+//Methods accept opts as a trailing variadic so existing call sites keep compiling.
+func firstOptions(opts []Options) Options {
+	if len(opts) == 0 {
+		return Options{}
+	}
+	return opts[0]
+}
+
+//ObjectInfo describes the metadata returned by HeadObject
+type ObjectInfo struct {
+	ContentLength        int64
+	ContentType          string
+	ETag                 string
+	LastModified         string
+	ServerSideEncryption string
+	SSEKMSKeyID          string
+	StorageClass         string
+	Metadata             map[string]string
+}
+
+//HeadObject returns metadata for key without fetching its body, including which
+//server-side encryption (if any) was used to store it. Pass opts to supply SSE-C customer
+//key headers, which S3 also requires on HEAD for objects encrypted with a customer-provided key.
+func (c *Client) HeadObject(key string, opts ...Options) (*ObjectInfo, error) {
+
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	firstOptions(opts).applyToHeadObjectInput(input)
+
+	result, err := c.svc.HeadObject(input)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		ServerSideEncryption: aws.StringValue(result.ServerSideEncryption),
+		SSEKMSKeyID:          aws.StringValue(result.SSEKMSKeyId),
+		ContentType:          aws.StringValue(result.ContentType),
+		ETag:                 aws.StringValue(result.ETag),
+		StorageClass:         aws.StringValue(result.StorageClass),
+		Metadata:             aws.StringValueMap(result.Metadata),
+	}
+	if result.ContentLength != nil {
+		info.ContentLength = *result.ContentLength
+	}
+	if result.LastModified != nil {
+		info.LastModified = result.LastModified.String()
+	}
+
+	return info, nil
+}
@@ -0,0 +1,157 @@
+package s3lib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+//GetPresignedPutURL returns a presigned URL a client can PUT to directly to upload key,
+//without the bytes passing through the application
+func (c *Client) GetPresignedPutURL(key string, expiration time.Duration, contentType string, contentLength int64) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if contentLength > 0 {
+		input.ContentLength = aws.Int64(contentLength)
+	}
+
+	req, _ := c.svc.PutObjectRequest(input)
+	return req.Presign(expiration)
+}
+
+//GetPresignedDeleteURL returns a presigned URL a client can DELETE directly to remove key
+func (c *Client) GetPresignedDeleteURL(key string, expiration time.Duration) (string, error) {
+	req, _ := c.svc.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(expiration)
+}
+
+//PostPolicy describes the constraints placed on a browser-based POST upload created by CreatePresignedPost
+type PostPolicy struct {
+
+	//Expiration is how long the policy document remains valid
+	Expiration time.Duration
+
+	//ContentType, if set, requires the uploaded object to have this exact content-type
+	ContentType string
+
+	//ContentLengthMin and ContentLengthMax, if either is non-zero, bound the size of the uploaded
+	//object to [ContentLengthMin, ContentLengthMax] bytes; both values are sent as given
+	ContentLengthMin int64
+	ContentLengthMax int64
+}
+
+//PresignedPost is the set of form fields a browser must submit, along with the URL to post to,
+//to upload directly to S3 via CreatePresignedPost
+type PresignedPost struct {
+	URL    string
+	Fields map[string]string
+}
+
+//CreatePresignedPost returns the form fields and policy document needed for a browser to upload
+//key directly to the bucket via a multipart/form-data POST, without proxying bytes through the application
+func (c *Client) CreatePresignedPost(key string, conditions PostPolicy) (*PresignedPost, error) {
+
+	if c.session == nil {
+		return nil, errors.New("s3lib: CreatePresignedPost requires a Client built with a session (NewClient, NewClientWithSession, or NewClientWithConfig), not NewClientWithS3API")
+	}
+
+	creds, err := c.session.Config.Credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiration := conditions.Expiration
+	if expiration == 0 {
+		expiration = 15 * time.Minute
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	credential := creds.AccessKeyID + "/" + shortDate + "/" + c.Region + "/s3/aws4_request"
+
+	policyConditions := []interface{}{
+		map[string]string{"bucket": c.Bucket},
+		map[string]string{"key": key},
+		map[string]string{"x-amz-credential": credential},
+		map[string]string{"x-amz-algorithm": "AWS4-HMAC-SHA256"},
+		map[string]string{"x-amz-date": amzDate},
+	}
+	if creds.SessionToken != "" {
+		policyConditions = append(policyConditions, map[string]string{"x-amz-security-token": creds.SessionToken})
+	}
+	if conditions.ContentType != "" {
+		policyConditions = append(policyConditions, map[string]string{"Content-Type": conditions.ContentType})
+	}
+	if conditions.ContentLengthMin > 0 || conditions.ContentLengthMax > 0 {
+		policyConditions = append(policyConditions, []interface{}{"content-length-range", conditions.ContentLengthMin, conditions.ContentLengthMax})
+	}
+
+	policyDoc := map[string]interface{}{
+		"expiration": now.Add(expiration).Format(time.RFC3339),
+		"conditions": policyConditions,
+	}
+
+	policyJSON, err := json.Marshal(policyDoc)
+	if err != nil {
+		return nil, err
+	}
+	policyB64 := base64.StdEncoding.EncodeToString(policyJSON)
+
+	signature := signPolicy(creds.SecretAccessKey, shortDate, c.Region, policyB64)
+
+	fields := map[string]string{
+		"key":              key,
+		"bucket":           c.Bucket,
+		"policy":           policyB64,
+		"x-amz-algorithm":  "AWS4-HMAC-SHA256",
+		"x-amz-credential": credential,
+		"x-amz-date":       amzDate,
+		"x-amz-signature":  signature,
+	}
+	if creds.SessionToken != "" {
+		fields["x-amz-security-token"] = creds.SessionToken
+	}
+	if conditions.ContentType != "" {
+		fields["Content-Type"] = conditions.ContentType
+	}
+
+	url := "https://" + c.Bucket + ".s3." + c.Region + ".amazonaws.com/"
+	if c.session.Config.Endpoint != nil && *c.session.Config.Endpoint != "" {
+		url = *c.session.Config.Endpoint + "/" + c.Bucket + "/"
+	}
+
+	return &PresignedPost{URL: url, Fields: fields}, nil
+}
+
+//signPolicy signs policyB64 following the SigV4 signing-key derivation chain
+//(see AWS "Signature Calculations for Authorization Header" for POST policies)
+func signPolicy(secretKey, shortDate, region, policyB64 string) string {
+	dateKey := hmacSHA256([]byte("AWS4"+secretKey), shortDate)
+	dateRegionKey := hmacSHA256(dateKey, region)
+	dateRegionServiceKey := hmacSHA256(dateRegionKey, "s3")
+	signingKey := hmacSHA256(dateRegionServiceKey, "aws4_request")
+	signature := hmacSHA256(signingKey, policyB64)
+	return hex.EncodeToString(signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,382 @@
+package s3lib
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+//SyncOptions controls how SyncUp/SyncDown walk, filter, and transfer files
+type SyncOptions struct {
+
+	//Delete removes objects/files on the destination that no longer exist on the source
+	Delete bool
+
+	//Include, if set, only syncs paths matching this regex
+	Include string
+
+	//Exclude, if set, skips paths matching this regex, applied after Include
+	Exclude string
+
+	//Concurrency is the number of files transferred in parallel (default 4)
+	Concurrency int
+}
+
+//workerCount returns o.Concurrency, or a sane default if it isn't set
+func (o SyncOptions) workerCount() int {
+	if o.Concurrency > 0 {
+		return o.Concurrency
+	}
+	return 4
+}
+
+//filter compiles Include/Exclude into a predicate over a relative path
+func (o SyncOptions) filter() (func(string) bool, error) {
+
+	var include, exclude *regexp.Regexp
+	var err error
+
+	if o.Include != "" {
+		include, err = regexp.Compile(o.Include)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.Exclude != "" {
+		exclude, err = regexp.Compile(o.Exclude)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(relPath string) bool {
+		if include != nil && !include.MatchString(relPath) {
+			return false
+		}
+		if exclude != nil && exclude.MatchString(relPath) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+//SyncUp uploads localDir to prefix, skipping files whose size and ETag already match
+//the destination object, uploading only what changed via a bounded worker pool.
+//When opts.Delete is true, destination objects with no matching local file are removed.
+func (c *Client) SyncUp(localDir, prefix string, opts SyncOptions) error {
+
+	keep, err := opts.filter()
+	if err != nil {
+		return err
+	}
+
+	destObjects, err := c.listDestination(prefix)
+	if err != nil {
+		return err
+	}
+
+	localFiles := map[string]string{} // relPath -> absolute path
+	err = filepath.Walk(localDir, func(p string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		if keep(relPath) {
+			localFiles[relPath] = p
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	type job struct {
+		key      string
+		filePath string
+	}
+	jobs := []job{}
+	for relPath, absPath := range localFiles {
+		key := path.Join(prefix, filepath.ToSlash(relPath))
+		dest, exists := destObjects[key]
+
+		fi, err := os.Stat(absPath)
+		if err != nil {
+			return err
+		}
+
+		if exists && objectUnchanged(fi.Size(), absPath, dest) {
+			continue
+		}
+		jobs = append(jobs, job{key: key, filePath: absPath})
+	}
+
+	if err := runWorkers(len(jobs), opts.workerCount(), func(i int) error {
+		j := jobs[i]
+		file, err := os.Open(j.filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = c.uploader.Upload(&s3manager.UploadInput{
+			Bucket: aws.String(c.Bucket),
+			Key:    aws.String(j.key),
+			Body:   file,
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if opts.Delete {
+		for key := range destObjects {
+			relPath, ok := relPathUnderPrefix(prefix, key)
+			if !ok {
+				continue // not actually nested under prefix (e.g. a string-sharing sibling key)
+			}
+			if !keep(relPath) {
+				continue // outside this sync's filter, not this run's business to delete
+			}
+			if _, ok := localFiles[filepath.FromSlash(relPath)]; !ok {
+				if err := c.DeleteObject(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+//SyncDown downloads objects under prefix into localDir, skipping files whose size and
+//ETag already match the local file, downloading only what changed via a bounded worker pool.
+//When opts.Delete is true, local files with no matching source object are removed.
+func (c *Client) SyncDown(prefix, localDir string, opts SyncOptions) error {
+
+	keep, err := opts.filter()
+	if err != nil {
+		return err
+	}
+
+	sourceObjects, err := c.listDestination(prefix)
+	if err != nil {
+		return err
+	}
+
+	type job struct {
+		key      string
+		filePath string
+	}
+	jobs := []job{}
+	seen := map[string]bool{}
+
+	for key, obj := range sourceObjects {
+		relPath, ok := relPathUnderPrefix(prefix, key)
+		if !ok {
+			continue // not actually nested under prefix (e.g. a string-sharing sibling key)
+		}
+		if !keep(relPath) {
+			continue
+		}
+		relPath = filepath.FromSlash(relPath)
+		seen[relPath] = true
+		dst := filepath.Join(localDir, relPath)
+
+		if fi, err := os.Stat(dst); err == nil && objectUnchanged(fi.Size(), dst, obj) {
+			continue
+		}
+		jobs = append(jobs, job{key: key, filePath: dst})
+	}
+
+	if err := runWorkers(len(jobs), opts.workerCount(), func(i int) error {
+		j := jobs[i]
+		if err := os.MkdirAll(filepath.Dir(j.filePath), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(j.filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = c.downloader.Download(f, &s3.GetObjectInput{
+			Bucket: aws.String(c.Bucket),
+			Key:    aws.String(j.key),
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if opts.Delete {
+		filepath.Walk(localDir, func(p string, f os.FileInfo, err error) error {
+			if err != nil || f.IsDir() {
+				return nil
+			}
+			relPath, err := filepath.Rel(localDir, p)
+			if err != nil {
+				return nil
+			}
+			if !keep(filepath.ToSlash(relPath)) {
+				return nil // outside this sync's filter, not this run's business to delete
+			}
+			if !seen[relPath] {
+				os.Remove(p)
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+//relPathUnderPrefix returns key's path relative to prefix, treating prefix as a directory
+//boundary rather than a bare string prefix: it requires key to fall under prefix+"/" (prefix
+//is normalized to end in "/" first), so a sibling key that merely shares prefix as a string
+//("users/alice2/evil.txt" vs. prefix "users/alice") is rejected rather than resolving to a
+//relative path with leading ".." components. ok is false if key isn't actually under prefix,
+//or if the resulting relative path isn't safe to join onto a local directory.
+func relPathUnderPrefix(prefix, key string) (relPath string, ok bool) {
+	boundary := prefix
+	if boundary != "" && !strings.HasSuffix(boundary, "/") {
+		boundary += "/"
+	}
+	if !strings.HasPrefix(key, boundary) {
+		return "", false
+	}
+	relPath = strings.TrimPrefix(key, boundary)
+
+	cleaned := filepath.Clean(filepath.FromSlash(relPath))
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) || filepath.IsAbs(cleaned) {
+		return "", false
+	}
+
+	return relPath, true
+}
+
+//listDestination gathers every object under prefix into a map keyed by its full key
+func (c *Client) listDestination(prefix string) (map[string]*s3.Object, error) {
+	objects, err := c.ListAll(prefix)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*s3.Object, len(objects))
+	for _, obj := range objects {
+		byKey[aws.StringValue(obj.Key)] = obj
+	}
+	return byKey, nil
+}
+
+//objectUnchanged reports whether the local file at filePath already matches obj,
+//comparing size first and then the quoted ETag (recomputing the multipart ETag if needed)
+func objectUnchanged(localSize int64, filePath string, obj *s3.Object) bool {
+	if obj.Size == nil || *obj.Size != localSize {
+		return false
+	}
+	etag, err := localETag(filePath, localSize)
+	if err != nil {
+		return false
+	}
+	return etag == aws.StringValue(obj.ETag)
+}
+
+//localETag computes the S3 ETag a file would have, matching the format s3manager produces:
+//a plain quoted MD5 for objects uploaded in a single part, or a quoted "hash-numParts" for
+//objects uploaded in multiple parts of s3manager.DefaultUploadPartSize bytes each.
+func localETag(filePath string, size int64) (string, error) {
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	const partSize = s3manager.DefaultUploadPartSize
+
+	if size <= partSize {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("\"%s\"", hex.EncodeToString(h.Sum(nil))), nil
+	}
+
+	partHashes := []byte{}
+	numParts := 0
+	buf := make([]byte, partSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			h := md5.Sum(buf[:n])
+			partHashes = append(partHashes, h[:]...)
+			numParts++
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sum := md5.Sum(partHashes)
+	return fmt.Sprintf("\"%s-%d\"", hex.EncodeToString(sum[:]), numParts), nil
+}
+
+//runWorkers runs fn(0..n-1) across a bounded pool of workers goroutines, returning the
+//first error encountered. An error does not stop the remaining jobs: all n are run.
+func runWorkers(n int, workers int, fn func(i int) error) error {
+
+	if n == 0 {
+		return nil
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs <- fn(i)
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < n; i++ {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}